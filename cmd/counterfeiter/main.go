@@ -0,0 +1,207 @@
+// Command counterfeiter generates a fake (test double) for a Go interface
+// and writes it to disk.
+//
+// Usage:
+//
+//	counterfeiter <package> <InterfaceName> [output-file]
+//
+// The fake is written to <package>/<package>fakes/fake_<interface>.go
+// unless output-file is given. counterfeiter is most often invoked via a
+// go:generate directive placed next to the interface it fakes:
+//
+//	//go:generate counterfeiter . Foo
+//
+// which, run through `go generate`, expands "." into the package
+// containing the directive.
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+
+	"github.com/fraenkel/counterfeiter/generator"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "counterfeiter:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	pkgPattern, interfaceName, outputFile, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := loadPackage(pkgPattern)
+	if err != nil {
+		return err
+	}
+
+	interfaceNode, resolved, err := findInterface(pkg, interfaceName)
+	if err != nil {
+		return err
+	}
+
+	fakePackageName := pkg.Types.Name() + "fakes"
+	src, err := generator.GenerateFake(
+		"Fake"+interfaceName,
+		fakePackageName,
+		pkg.PkgPath,
+		interfaceName,
+		interfaceNode,
+		resolved,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("generating fake for %s: %w", interfaceName, err)
+	}
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return fmt.Errorf("formatting generated fake: %w", err)
+	}
+
+	if outputFile == "" {
+		outputFile = defaultOutputFile(pkg, fakePackageName, interfaceName)
+	}
+
+	withImports, err := imports.Process(outputFile, formatted, nil)
+	if err != nil {
+		return fmt.Errorf("resolving imports for %s: %w", outputFile, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(outputFile), err)
+	}
+
+	return os.WriteFile(outputFile, withImports, 0o644)
+}
+
+// parseArgs accepts the documented <package> <InterfaceName> [output-file]
+// form, plus a one-argument <InterfaceName> form for go:generate directives
+// like "//go:generate counterfeiter Foo" that omit the package. go generate
+// sets $GOFILE to the source file containing the directive being expanded,
+// so the package pattern for the 1-arg form is derived from $GOFILE's
+// directory (falling back to "." when $GOFILE isn't set, e.g. when run
+// outside of go generate).
+func parseArgs(args []string) (pkgPattern, interfaceName, outputFile string, err error) {
+	switch len(args) {
+	case 1:
+		return goGeneratePackagePattern(), args[0], "", nil
+	case 2:
+		return args[0], args[1], "", nil
+	case 3:
+		return args[0], args[1], args[2], nil
+	default:
+		return "", "", "", fmt.Errorf("usage: counterfeiter <package> <InterfaceName> [output-file]")
+	}
+}
+
+// goGeneratePackagePattern derives a package pattern from $GOFILE, the
+// source file go generate points at the directive it is expanding, so that
+// "//go:generate counterfeiter Foo" resolves to the package declaring Foo
+// even when go generate is invoked from outside that package's directory.
+func goGeneratePackagePattern() string {
+	goFile := os.Getenv("GOFILE")
+	if goFile == "" {
+		return "."
+	}
+	if dir := filepath.Dir(goFile); dir != "." {
+		return "./" + dir
+	}
+	return "."
+}
+
+func loadPackage(pattern string) (*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedSyntax |
+			packages.NeedImports |
+			packages.NeedDeps,
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", pattern, err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("%s has errors", pattern)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected exactly one package for %s, got %d", pattern, len(pkgs))
+	}
+
+	return pkgs[0], nil
+}
+
+// findInterface locates interfaceName's declaration in pkg, returning its
+// type-checked *types.Interface (used to expand embedded interfaces and
+// qualify cross-package types) along with, where one exists, the matching
+// *ast.InterfaceType literal (for source-level details GenerateFake prefers,
+// like original parameter names). interfaceName may also name a type alias
+// (type MyReader = io.Reader) or a defined type whose underlying type is an
+// interface (type Derived Base); neither has an inline interface literal of
+// its own, so the *ast.InterfaceType return is nil for those and GenerateFake
+// falls back to resolved alone.
+func findInterface(pkg *packages.Package, interfaceName string) (*ast.InterfaceType, *types.Interface, error) {
+	obj := pkg.Types.Scope().Lookup(interfaceName)
+	if obj == nil {
+		return nil, nil, fmt.Errorf("no such interface: %s.%s", pkg.PkgPath, interfaceName)
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s.%s is not a named type", pkg.PkgPath, interfaceName)
+	}
+
+	resolved, ok := named.Underlying().(*types.Interface)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s.%s is not an interface", pkg.PkgPath, interfaceName)
+	}
+	resolved = resolved.Complete()
+
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok || typeSpec.Name.Name != interfaceName {
+					continue
+				}
+				if interfaceNode, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+					return interfaceNode, resolved, nil
+				}
+			}
+		}
+	}
+
+	return nil, resolved, nil
+}
+
+// defaultOutputFile places the fake alongside the interface's own package
+// directory (not the current working directory, which for the documented
+// //go:generate counterfeiter . Foo invocation is already that directory).
+func defaultOutputFile(pkg *packages.Package, fakePackageName, interfaceName string) string {
+	pkgDir := "."
+	if len(pkg.GoFiles) > 0 {
+		pkgDir = filepath.Dir(pkg.GoFiles[0])
+	}
+	return filepath.Join(pkgDir, fakePackageName, "fake_"+strings.ToLower(interfaceName)+".go")
+}
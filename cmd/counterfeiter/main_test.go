@@ -0,0 +1,184 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestDefaultOutputFile(t *testing.T) {
+	pkg := &packages.Package{GoFiles: []string{filepath.Join("/src", "variadicpkg", "foo.go")}}
+
+	got := defaultOutputFile(pkg, "variadicpkgfakes", "Fooer")
+	want := filepath.Join("/src", "variadicpkg", "variadicpkgfakes", "fake_fooer.go")
+	if got != want {
+		t.Errorf("defaultOutputFile() = %q, want %q", got, want)
+	}
+}
+
+func TestGoGeneratePackagePattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		goFile string
+		want   string
+	}{
+		{"unset", "", "."},
+		{"same directory", "foo.go", "."},
+		{"nested directory", filepath.Join("sub", "dir", "foo.go"), "./" + filepath.Join("sub", "dir")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOFILE", tt.goFile)
+			if got := goGeneratePackagePattern(); got != tt.want {
+				t.Errorf("goGeneratePackagePattern() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// loadFixture writes files (path -> contents) under a fresh module rooted
+// at t.TempDir() and loads dir (relative to that root) with full type info,
+// the same way loadPackage does.
+func loadFixture(t *testing.T, files map[string]string, dir string) *packages.Package {
+	t.Helper()
+
+	root := t.TempDir()
+	if _, ok := files["go.mod"]; !ok {
+		files["go.mod"] = "module fixture\n\ngo 1.21\n"
+	}
+	for path, contents := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &packages.Config{
+		Dir: filepath.Join(root, dir),
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedSyntax |
+			packages.NeedImports |
+			packages.NeedDeps,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		t.Fatalf("loading fixture: %v", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 || len(pkgs) != 1 {
+		t.Fatalf("expected exactly one error-free package, got %d", len(pkgs))
+	}
+	return pkgs[0]
+}
+
+func TestFindInterface_TypeAlias(t *testing.T) {
+	pkg := loadFixture(t, map[string]string{
+		"aliaspkg/iface.go": `package aliaspkg
+
+import "io"
+
+type MyReader = io.Reader
+`,
+	}, "aliaspkg")
+
+	node, resolved, err := findInterface(pkg, "MyReader")
+	if err != nil {
+		t.Fatalf("findInterface: %v", err)
+	}
+	if node != nil {
+		t.Errorf("expected a nil *ast.InterfaceType for an alias, got %#v", node)
+	}
+	if resolved == nil || resolved.NumMethods() != 1 {
+		t.Fatalf("expected the resolved io.Reader interface, got %#v", resolved)
+	}
+}
+
+func TestFindInterface_DerivedType(t *testing.T) {
+	pkg := loadFixture(t, map[string]string{
+		"derivedpkg/iface.go": `package derivedpkg
+
+type Base interface {
+	Do() error
+}
+
+type Derived Base
+`,
+	}, "derivedpkg")
+
+	node, resolved, err := findInterface(pkg, "Derived")
+	if err != nil {
+		t.Fatalf("findInterface: %v", err)
+	}
+	if node != nil {
+		t.Errorf("expected a nil *ast.InterfaceType for a defined type over another interface, got %#v", node)
+	}
+	if resolved == nil || resolved.NumMethods() != 1 {
+		t.Fatalf("expected the resolved Base interface, got %#v", resolved)
+	}
+}
+
+// TestRun_CrossPackageImport is the round-trip check chunk0-3's assertion
+// and chunk0-4's import qualification exist to support: generating a fake
+// into its own "foofakes" package for an interface with a cross-package
+// parameter type, then actually compiling the result.
+func TestRun_CrossPackageImport(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module fixture\n\ngo 1.21\n",
+		"storepkg/store.go": `package storepkg
+
+type Item struct {
+	Name string
+}
+`,
+		"userpkg/user.go": `package userpkg
+
+import "fixture/storepkg"
+
+type Adder interface {
+	Add(item storepkg.Item) error
+}
+`,
+	}
+	for path, contents := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(filepath.Join(root, "userpkg")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run([]string{".", "Adder"}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	out := filepath.Join(root, "userpkg", "userpkgfakes", "fake_adder.go")
+	if _, err := os.Stat(out); err != nil {
+		t.Fatalf("expected %s to exist: %v", out, err)
+	}
+
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = root
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated fake does not compile: %v\n%s", err, out)
+	}
+}
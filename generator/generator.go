@@ -2,19 +2,41 @@ package generator
 
 import (
 	"bytes"
+	"fmt"
 	"go/ast"
 	"go/printer"
 	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+// GenerateFake renders the source of a fake implementing interfaceNode.
+// interfacePackagePath and interfaceName identify the interface being faked
+// (e.g. "io" and "ReadCloser") so a compile-time assertion can be emitted;
+// pass "" for interfacePackagePath when the interface lives in packageName
+// itself. resolvedInterface, when non-nil, is the type-checked form of the
+// same interface; it drives both embedded-interface expansion and import
+// resolution, and any package it mentions is qualified correctly regardless
+// of importPathsByAlias. When resolvedInterface is nil, GenerateFake falls
+// back to interfaceNode's raw (possibly qualifier-only) AST, and
+// importPathsByAlias supplies the import path for each package qualifier
+// (e.g. {"context": "context"}) so the generated import block stays correct.
 func GenerateFake(
 	structName, packageName string,
-	interfaceNode *ast.InterfaceType) (string, error) {
+	interfacePackagePath, interfaceName string,
+	interfaceNode *ast.InterfaceType,
+	resolvedInterface *types.Interface,
+	importPathsByAlias map[string]string) (string, error) {
 	gen := generator{
-		structName:    structName,
-		packageName:   packageName,
-		interfaceNode: interfaceNode,
+		structName:           structName,
+		packageName:          packageName,
+		interfacePackagePath: interfacePackagePath,
+		interfaceName:        interfaceName,
+		interfaceNode:        interfaceNode,
+		resolvedInterface:    resolvedInterface,
+		importPathsByAlias:   importPathsByAlias,
 	}
 
 	buf := new(bytes.Buffer)
@@ -23,31 +45,217 @@ func GenerateFake(
 }
 
 type generator struct {
-	structName    string
-	packageName   string
-	interfaceNode *ast.InterfaceType
+	structName           string
+	packageName          string
+	interfacePackagePath string
+	interfaceName        string
+	interfaceNode        *ast.InterfaceType
+	resolvedInterface    *types.Interface
+	importPathsByAlias   map[string]string
+}
+
+// samePackage reports whether the fake is being generated into the same
+// package that declares the interface, in which case references to that
+// package's own types must stay unqualified.
+func (gen *generator) samePackage() bool {
+	return gen.interfacePackagePath == "" || packageQualifier(gen.interfacePackagePath) == gen.packageName
 }
 
 func (gen *generator) SourceFile() ast.Node {
+	decls := []ast.Decl{gen.imports(), gen.typeDecl()}
+	if assertion := gen.assertionDecl(); assertion != nil {
+		decls = append(decls, assertion)
+	}
+	decls = append(decls, gen.constructorDecl())
+	decls = append(decls, gen.methodDecls()...)
+
 	return &ast.File{
-		Name: &ast.Ident{Name: gen.packageName},
-		Decls: append([]ast.Decl{
-			gen.imports(),
-			gen.typeDecl(),
-			gen.constructorDecl(),
-		}, gen.methodDecls()...),
+		Name:  &ast.Ident{Name: gen.packageName},
+		Decls: decls,
 	}
 }
 
-func (gen *generator) imports() ast.Decl {
+// assertionDecl emits `var _ pkg.Iface = new(FakeIface)` so the compiler
+// verifies the fake satisfies the interface it claims to fake. It returns
+// nil when the caller didn't supply an interface name to assert against.
+func (gen *generator) assertionDecl() ast.Decl {
+	if gen.interfaceName == "" {
+		return nil
+	}
+
+	var ifaceType ast.Expr = ast.NewIdent(gen.interfaceName)
+	if !gen.samePackage() {
+		ifaceType = &ast.SelectorExpr{
+			X:   ast.NewIdent(gen.ifacePackageName()),
+			Sel: ast.NewIdent(gen.interfaceName),
+		}
+	}
+
 	return &ast.GenDecl{
-		Tok: token.IMPORT,
-		Specs: []ast.Spec{&ast.ImportSpec{
-			Path: &ast.BasicLit{
-				Kind:  token.STRING,
-				Value: `"sync"`,
+		Tok: token.VAR,
+		Specs: []ast.Spec{
+			&ast.ValueSpec{
+				Names: []*ast.Ident{ast.NewIdent("_")},
+				Type:  ifaceType,
+				Values: []ast.Expr{
+					&ast.CallExpr{
+						Fun:  ast.NewIdent("new"),
+						Args: []ast.Expr{ast.NewIdent(gen.structName)},
+					},
+				},
 			},
-		}},
+		},
+	}
+}
+
+func packageQualifier(importPath string) string {
+	if i := strings.LastIndex(importPath, "/"); i >= 0 {
+		return importPath[i+1:]
+	}
+	return importPath
+}
+
+// ifacePackageName returns the Go identifier the interface's own package is
+// referred to by, preferring resolvedInterface's type-checked package name
+// (correct even when a package's name differs from its import path's last
+// segment, e.g. "yaml" for "gopkg.in/yaml.v2") and falling back to
+// importPathsByAlias, then to a guess from the import path, when no
+// resolved interface is available.
+func (gen *generator) ifacePackageName() string {
+	if gen.resolvedInterface != nil {
+		for i := 0; i < gen.resolvedInterface.NumMethods(); i++ {
+			if pkg := gen.resolvedInterface.Method(i).Pkg(); pkg != nil && pkg.Path() == gen.interfacePackagePath {
+				return pkg.Name()
+			}
+		}
+	}
+	for alias, path := range gen.importPathsByAlias {
+		if path == gen.interfacePackagePath {
+			return alias
+		}
+	}
+	return packageQualifier(gen.interfacePackagePath)
+}
+
+// imports builds the fake's import block: "sync" for the embedded RWMutex
+// plus every package referenced by a faked method's parameter or result
+// types, so the generated file compiles without hand-editing.
+func (gen *generator) imports() ast.Decl {
+	specs := []ast.Spec{importSpec("", `"sync"`)}
+	for _, path := range gen.importedPackages() {
+		specs = append(specs, importSpec(path.alias, strconv.Quote(path.path)))
+	}
+
+	return &ast.GenDecl{
+		Tok:   token.IMPORT,
+		Specs: specs,
+	}
+}
+
+func importSpec(alias, quotedPath string) *ast.ImportSpec {
+	spec := &ast.ImportSpec{
+		Path: &ast.BasicLit{Kind: token.STRING, Value: quotedPath},
+	}
+	if alias != "" {
+		spec.Name = ast.NewIdent(alias)
+	}
+	return spec
+}
+
+type importedPackage struct {
+	path  string
+	alias string
+}
+
+// importedPackages returns, in a stable order, every package that the
+// generated method signatures reference.
+func (gen *generator) importedPackages() []importedPackage {
+	byPath := map[string]importedPackage{}
+
+	if !gen.samePackage() {
+		path := gen.interfacePackagePath
+		byPath[path] = importedPackage{path: path, alias: aliasFor(gen.ifacePackageName(), path)}
+	}
+
+	if gen.resolvedInterface != nil {
+		for i := 0; i < gen.resolvedInterface.NumMethods(); i++ {
+			fn := gen.resolvedInterface.Method(i)
+			collectPackages(fn.Type(), gen.interfacePackagePath, gen.samePackage(), byPath)
+		}
+	} else {
+		for _, method := range gen.interfaceNode.Methods.List {
+			if len(method.Names) == 0 {
+				continue
+			}
+			ast.Inspect(method.Type, func(n ast.Node) bool {
+				sel, ok := n.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				ident, ok := sel.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				if path, ok := gen.importPathsByAlias[ident.Name]; ok {
+					byPath[path] = importedPackage{path: path, alias: aliasFor(ident.Name, path)}
+				}
+				return true
+			})
+		}
+	}
+
+	paths := make([]string, 0, len(byPath))
+	for path := range byPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	packages := make([]importedPackage, len(paths))
+	for i, path := range paths {
+		packages[i] = byPath[path]
+	}
+	return packages
+}
+
+func aliasFor(qualifier, path string) string {
+	if qualifier == packageQualifier(path) {
+		return ""
+	}
+	return qualifier
+}
+
+// collectPackages walks t (and, for *types.Signature, its parameter/result
+// types) gathering every package referenced by a named type, skipping the
+// interface's own package when the fake is generated into it directly.
+func collectPackages(t types.Type, ifacePkgPath string, samePackage bool, into map[string]importedPackage) {
+	switch t := t.(type) {
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() == nil || (samePackage && obj.Pkg().Path() == ifacePkgPath) {
+			return
+		}
+		into[obj.Pkg().Path()] = importedPackage{
+			path:  obj.Pkg().Path(),
+			alias: aliasFor(obj.Pkg().Name(), obj.Pkg().Path()),
+		}
+	case *types.Pointer:
+		collectPackages(t.Elem(), ifacePkgPath, samePackage, into)
+	case *types.Slice:
+		collectPackages(t.Elem(), ifacePkgPath, samePackage, into)
+	case *types.Array:
+		collectPackages(t.Elem(), ifacePkgPath, samePackage, into)
+	case *types.Map:
+		collectPackages(t.Key(), ifacePkgPath, samePackage, into)
+		collectPackages(t.Elem(), ifacePkgPath, samePackage, into)
+	case *types.Chan:
+		collectPackages(t.Elem(), ifacePkgPath, samePackage, into)
+	case *types.Signature:
+		for i := 0; i < t.Params().Len(); i++ {
+			collectPackages(t.Params().At(i).Type(), ifacePkgPath, samePackage, into)
+		}
+		for i := 0; i < t.Results().Len(); i++ {
+			collectPackages(t.Results().At(i).Type(), ifacePkgPath, samePackage, into)
+		}
 	}
 }
 
@@ -98,14 +306,49 @@ func (gen *generator) constructorDecl() ast.Decl {
 	}
 }
 
+// methods returns the interface's methods with any embedded interfaces
+// (fields in interfaceNode.Methods.List whose Names are empty) expanded
+// into individual named methods, using resolvedInterface's flattened
+// method set to discover the signatures those embeds contribute. When
+// resolvedInterface is available it is used for every method (not just
+// embedded ones), since it also gives us fully qualified types for
+// cross-package identifiers that the raw AST only has bare names for.
+// Without resolvedInterface, embedded interfaces are skipped and only the
+// methods declared directly in interfaceNode are returned.
+func (gen *generator) methods() []*ast.Field {
+	if gen.resolvedInterface != nil {
+		methods := []*ast.Field{}
+		for i := 0; i < gen.resolvedInterface.NumMethods(); i++ {
+			fn := gen.resolvedInterface.Method(i)
+			methods = append(methods, gen.fieldForSignature(fn.Name(), fn.Type().(*types.Signature)))
+		}
+		return methods
+	}
+
+	methods := []*ast.Field{}
+	for _, method := range gen.interfaceNode.Methods.List {
+		if len(method.Names) == 0 {
+			continue
+		}
+		methods = append(methods, method)
+	}
+	return methods
+}
+
 func (gen *generator) methodDecls() []ast.Decl {
 	result := []ast.Decl{}
-	for _, method := range gen.interfaceNode.Methods.List {
+	for _, method := range gen.methods() {
 		result = append(
 			result,
 			gen.methodImplementation(method),
 			gen.callsListGetter(method),
+			gen.callCountGetter(method),
+			gen.argsForCallGetter(method),
 		)
+
+		if hasResults(method) {
+			result = append(result, gen.returnsSetter(method))
+		}
 	}
 	return result
 }
@@ -120,7 +363,7 @@ func (gen *generator) structFields() []*ast.Field {
 		},
 	}
 
-	for _, method := range gen.interfaceNode.Methods.List {
+	for _, method := range gen.methods() {
 		result = append(
 			result,
 
@@ -135,41 +378,117 @@ func (gen *generator) structFields() []*ast.Field {
 					Elt: gen.argsStructTypeForMethod(method),
 				},
 			})
+
+		if hasResults(method) {
+			result = append(result, &ast.Field{
+				Names: []*ast.Ident{returnsFieldIdent(method)},
+				Type:  gen.returnsStructTypeForMethod(method),
+			})
+		}
 	}
 
 	return result
 }
 
 func (gen *generator) argsStructTypeForMethod(method *ast.Field) *ast.StructType {
-	methodType := method.Type.(*ast.FuncType)
+	fields := []*ast.Field{}
+	for _, field := range paramFields(method) {
+		fields = append(fields, &ast.Field{
+			Type:  storageType(field.Type),
+			Names: []*ast.Ident{ast.NewIdent(publicize(field.Names[0].Name))},
+		})
+	}
 
-	paramFields := []*ast.Field{}
-	for _, field := range methodType.Params.List {
-		paramFields = append(paramFields, &ast.Field{
+	return &ast.StructType{
+		Fields: &ast.FieldList{List: fields},
+	}
+}
+
+// storageType converts t to the type used to store or return a value
+// outside of a function's own parameter list. "...T" is only legal in a
+// func's parameter position, so a variadic parameter's "...T" becomes the
+// equivalent slice type "[]T" when it's recorded in an args struct or
+// returned from ArgsForCall; every other type is returned unchanged.
+func storageType(t ast.Expr) ast.Expr {
+	if ell, ok := t.(*ast.Ellipsis); ok {
+		return &ast.ArrayType{Elt: ell.Elt}
+	}
+	return t
+}
+
+// isVariadic reports whether method's last parameter is "...T".
+func isVariadic(method *ast.Field) bool {
+	params := method.Type.(*ast.FuncType).Params
+	if params == nil || len(params.List) == 0 {
+		return false
+	}
+	_, ok := params.List[len(params.List)-1].Type.(*ast.Ellipsis)
+	return ok
+}
+
+func (gen *generator) returnsStructTypeForMethod(method *ast.Field) *ast.StructType {
+	fields := []*ast.Field{}
+	for _, field := range resultFields(method) {
+		fields = append(fields, &ast.Field{
 			Type:  field.Type,
-			Names: []*ast.Ident{ast.NewIdent(publicize(nameForMethodParam(field)))},
+			Names: []*ast.Ident{field.Names[0]},
 		})
 	}
 
 	return &ast.StructType{
-		Fields: &ast.FieldList{List: paramFields},
+		Fields: &ast.FieldList{List: fields},
 	}
 }
 
-func nameForMethodParam(param *ast.Field) string {
-	if len(param.Names) > 0 {
-		return param.Names[0].Name
-	} else {
-		panic("Don't handle anonymous args yet!")
+// paramFields returns one *ast.Field per parameter of method, expanding
+// multi-name fields (e.g. "a, b string") into individual fields and
+// synthesizing stable names ("arg1", "arg2", ...) for parameters that
+// weren't given a name in the interface declaration.
+func paramFields(method *ast.Field) []*ast.Field {
+	return flattenFieldList(method.Type.(*ast.FuncType).Params, "arg")
+}
+
+// resultFields is the result-list counterpart of paramFields.
+func resultFields(method *ast.Field) []*ast.Field {
+	return flattenFieldList(method.Type.(*ast.FuncType).Results, "result")
+}
+
+func flattenFieldList(fieldList *ast.FieldList, namePrefix string) []*ast.Field {
+	if fieldList == nil {
+		return nil
 	}
+
+	fields := []*ast.Field{}
+	i := 0
+	for _, field := range fieldList.List {
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{nil}
+		}
+
+		for _, name := range names {
+			i++
+			ident := name
+			if ident == nil {
+				ident = ast.NewIdent(fmt.Sprintf("%s%d", namePrefix, i))
+			}
+			fields = append(fields, &ast.Field{
+				Names: []*ast.Ident{ident},
+				Type:  field.Type,
+			})
+		}
+	}
+
+	return fields
 }
 
 func (gen *generator) methodImplementation(method *ast.Field) *ast.FuncDecl {
 	methodType := method.Type.(*ast.FuncType)
 
+	namedParams := paramFields(method)
 	forwardArgs := []ast.Expr{}
-	for _, field := range methodType.Params.List {
-		forwardArgs = append(forwardArgs, ast.NewIdent(nameForMethodParam(field)))
+	for _, field := range namedParams {
+		forwardArgs = append(forwardArgs, ast.NewIdent(field.Names[0].Name))
 	}
 
 	forwardCall := &ast.CallExpr{
@@ -179,70 +498,75 @@ func (gen *generator) methodImplementation(method *ast.Field) *ast.FuncDecl {
 		},
 		Args: forwardArgs,
 	}
+	if isVariadic(method) {
+		// Spread the trailing []T back into "...T" when forwarding to the
+		// stub, whose own signature still declares the parameter variadic.
+		forwardCall.Ellipsis = token.Pos(1)
+	}
 
-	var callStatement ast.Stmt
-	if methodType.Results != nil {
-		callStatement = &ast.ReturnStmt{
+	var stubCallStatement ast.Stmt
+	if hasResults(method) {
+		stubCallStatement = &ast.ReturnStmt{
 			Results: []ast.Expr{forwardCall},
 		}
 	} else {
-		callStatement = &ast.ExprStmt{
+		stubCallStatement = &ast.ExprStmt{
 			X: forwardCall,
 		}
 	}
 
-	return &ast.FuncDecl{
-		Name: method.Names[0],
-		Type: methodType,
-		Recv: &ast.FieldList{
-			List: []*ast.Field{
-				{
-					Names: []*ast.Ident{receiverIdent()},
-					Type:  &ast.StarExpr{X: ast.NewIdent(gen.structName)},
-				},
-			},
-		},
-		Body: &ast.BlockStmt{
-			List: []ast.Stmt{
-				&ast.ExprStmt{
-					X: &ast.CallExpr{
-						Fun: &ast.SelectorExpr{
-							X:   receiverIdent(),
-							Sel: ast.NewIdent("Lock"),
-						},
-					},
-				},
-				&ast.DeferStmt{
-					Call: &ast.CallExpr{
-						Fun: &ast.SelectorExpr{
-							X:   receiverIdent(),
-							Sel: ast.NewIdent("Unlock"),
-						},
-					},
-				},
-				&ast.AssignStmt{
-					Tok: token.ASSIGN,
-					Lhs: []ast.Expr{&ast.SelectorExpr{
+	body := []ast.Stmt{
+		lockStmt(),
+		&ast.AssignStmt{
+			Tok: token.ASSIGN,
+			Lhs: []ast.Expr{&ast.SelectorExpr{
+				X:   receiverIdent(),
+				Sel: callsListFieldIdent(method),
+			}},
+			Rhs: []ast.Expr{&ast.CallExpr{
+				Fun: ast.NewIdent("append"),
+				Args: []ast.Expr{
+					&ast.SelectorExpr{
 						X:   receiverIdent(),
 						Sel: callsListFieldIdent(method),
-					}},
-					Rhs: []ast.Expr{&ast.CallExpr{
-						Fun: ast.NewIdent("append"),
-						Args: []ast.Expr{
-							&ast.SelectorExpr{
-								X:   receiverIdent(),
-								Sel: callsListFieldIdent(method),
-							},
-							&ast.CompositeLit{
-								Type: gen.argsStructTypeForMethod(method),
-								Elts: forwardArgs,
-							},
-						},
-					}},
+					},
+					&ast.CompositeLit{
+						Type: gen.argsStructTypeForMethod(method),
+						Elts: forwardArgs,
+					},
 				},
-				callStatement,
+			}},
+		},
+		unlockStmt(),
+		&ast.IfStmt{
+			Cond: &ast.BinaryExpr{
+				X:  &ast.SelectorExpr{X: receiverIdent(), Sel: methodImplFuncIdent(method)},
+				Op: token.NEQ,
+				Y:  ast.NewIdent("nil"),
 			},
+			Body: &ast.BlockStmt{List: []ast.Stmt{stubCallStatement}},
+		},
+	}
+
+	if hasResults(method) {
+		returnExprs := []ast.Expr{}
+		for _, field := range resultFields(method) {
+			returnExprs = append(returnExprs, &ast.SelectorExpr{
+				X:   &ast.SelectorExpr{X: receiverIdent(), Sel: returnsFieldIdent(method)},
+				Sel: ast.NewIdent(field.Names[0].Name),
+			})
+		}
+		body = append(body, &ast.ReturnStmt{Results: returnExprs})
+	}
+
+	return &ast.FuncDecl{
+		Name: method.Names[0],
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: namedParams},
+			Results: methodType.Results,
 		},
+		Recv: gen.receiver(),
+		Body: &ast.BlockStmt{List: body},
 	}
 }
 
@@ -251,44 +575,51 @@ func (gen *generator) callsListGetter(method *ast.Field) *ast.FuncDecl {
 		Name: callsListMethodIdent(method),
 		Type: &ast.FuncType{
 			Results: &ast.FieldList{List: []*ast.Field{
-				&ast.Field{
+				{
 					Type: &ast.ArrayType{
 						Elt: gen.argsStructTypeForMethod(method),
 					},
 				},
 			}},
 		},
-		Recv: &ast.FieldList{
-			List: []*ast.Field{
-				{
-					Names: []*ast.Ident{receiverIdent()},
-					Type:  &ast.StarExpr{X: ast.NewIdent(gen.structName)},
-				},
-			},
-		},
+		Recv: gen.receiver(),
 		Body: &ast.BlockStmt{
 			List: []ast.Stmt{
-				&ast.ExprStmt{
-					X: &ast.CallExpr{
-						Fun: &ast.SelectorExpr{
-							X:   receiverIdent(),
-							Sel: ast.NewIdent("RLock"),
-						},
-					},
-				},
-				&ast.DeferStmt{
-					Call: &ast.CallExpr{
-						Fun: &ast.SelectorExpr{
+				rlockStmt(),
+				deferRUnlockStmt(),
+				&ast.ReturnStmt{
+					Results: []ast.Expr{
+						&ast.SelectorExpr{
 							X:   receiverIdent(),
-							Sel: ast.NewIdent("RUnlock"),
+							Sel: callsListFieldIdent(method),
 						},
 					},
 				},
+			},
+		},
+	}
+}
+
+func (gen *generator) callCountGetter(method *ast.Field) *ast.FuncDecl {
+	return &ast.FuncDecl{
+		Name: ast.NewIdent(method.Names[0].Name + "CallCount"),
+		Type: &ast.FuncType{
+			Results: &ast.FieldList{List: []*ast.Field{
+				{Type: ast.NewIdent("int")},
+			}},
+		},
+		Recv: gen.receiver(),
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				rlockStmt(),
+				deferRUnlockStmt(),
 				&ast.ReturnStmt{
 					Results: []ast.Expr{
-						&ast.SelectorExpr{
-							X:   receiverIdent(),
-							Sel: callsListFieldIdent(method),
+						&ast.CallExpr{
+							Fun: ast.NewIdent("len"),
+							Args: []ast.Expr{
+								&ast.SelectorExpr{X: receiverIdent(), Sel: callsListFieldIdent(method)},
+							},
 						},
 					},
 				},
@@ -297,10 +628,111 @@ func (gen *generator) callsListGetter(method *ast.Field) *ast.FuncDecl {
 	}
 }
 
+func (gen *generator) argsForCallGetter(method *ast.Field) *ast.FuncDecl {
+	results := []*ast.Field{}
+	returnExprs := []ast.Expr{}
+	for _, field := range paramFields(method) {
+		results = append(results, &ast.Field{Type: storageType(field.Type)})
+		returnExprs = append(returnExprs, &ast.SelectorExpr{
+			X: &ast.IndexExpr{
+				X:     &ast.SelectorExpr{X: receiverIdent(), Sel: callsListFieldIdent(method)},
+				Index: ast.NewIdent("i"),
+			},
+			Sel: ast.NewIdent(publicize(field.Names[0].Name)),
+		})
+	}
+
+	return &ast.FuncDecl{
+		Name: ast.NewIdent(method.Names[0].Name + "ArgsForCall"),
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{List: []*ast.Field{
+				{Names: []*ast.Ident{ast.NewIdent("i")}, Type: ast.NewIdent("int")},
+			}},
+			Results: &ast.FieldList{List: results},
+		},
+		Recv: gen.receiver(),
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				rlockStmt(),
+				deferRUnlockStmt(),
+				&ast.ReturnStmt{Results: returnExprs},
+			},
+		},
+	}
+}
+
+func (gen *generator) returnsSetter(method *ast.Field) *ast.FuncDecl {
+	params := []*ast.Field{}
+	elts := []ast.Expr{}
+	for _, field := range resultFields(method) {
+		params = append(params, &ast.Field{
+			Names: []*ast.Ident{field.Names[0]},
+			Type:  field.Type,
+		})
+		elts = append(elts, ast.NewIdent(field.Names[0].Name))
+	}
+
+	return &ast.FuncDecl{
+		Name: ast.NewIdent(method.Names[0].Name + "Returns"),
+		Type: &ast.FuncType{
+			Params: &ast.FieldList{List: params},
+		},
+		Recv: gen.receiver(),
+		Body: &ast.BlockStmt{
+			List: []ast.Stmt{
+				lockStmt(),
+				deferUnlockStmt(),
+				&ast.AssignStmt{
+					Tok: token.ASSIGN,
+					Lhs: []ast.Expr{&ast.SelectorExpr{X: receiverIdent(), Sel: returnsFieldIdent(method)}},
+					Rhs: []ast.Expr{&ast.CompositeLit{
+						Type: gen.returnsStructTypeForMethod(method),
+						Elts: elts,
+					}},
+				},
+			},
+		},
+	}
+}
+
+func (gen *generator) receiver() *ast.FieldList {
+	return &ast.FieldList{
+		List: []*ast.Field{
+			{
+				Names: []*ast.Ident{receiverIdent()},
+				Type:  &ast.StarExpr{X: ast.NewIdent(gen.structName)},
+			},
+		},
+	}
+}
+
+func lockStmt() ast.Stmt         { return mutexCallStmt("Lock") }
+func unlockStmt() ast.Stmt       { return mutexCallStmt("Unlock") }
+func rlockStmt() ast.Stmt        { return mutexCallStmt("RLock") }
+func deferUnlockStmt() ast.Stmt  { return &ast.DeferStmt{Call: mutexCall("Unlock")} }
+func deferRUnlockStmt() ast.Stmt { return &ast.DeferStmt{Call: mutexCall("RUnlock")} }
+
+func mutexCallStmt(method string) ast.Stmt {
+	return &ast.ExprStmt{X: mutexCall(method)}
+}
+
+func mutexCall(method string) *ast.CallExpr {
+	return &ast.CallExpr{
+		Fun: &ast.SelectorExpr{
+			X:   receiverIdent(),
+			Sel: ast.NewIdent(method),
+		},
+	}
+}
+
 func receiverIdent() *ast.Ident {
 	return ast.NewIdent("fake")
 }
 
+func hasResults(method *ast.Field) bool {
+	return method.Type.(*ast.FuncType).Results != nil
+}
+
 func callsListMethodIdent(method *ast.Field) *ast.Ident {
 	return ast.NewIdent(method.Names[0].Name + "Calls")
 }
@@ -309,6 +741,10 @@ func callsListFieldIdent(method *ast.Field) *ast.Ident {
 	return ast.NewIdent(privatize(callsListMethodIdent(method).Name))
 }
 
+func returnsFieldIdent(method *ast.Field) *ast.Ident {
+	return ast.NewIdent(privatize(method.Names[0].Name + "Returns"))
+}
+
 func methodImplFuncIdent(method *ast.Field) *ast.Ident {
 	return ast.NewIdent(method.Names[0].Name + "Stub")
 }
@@ -319,4 +755,99 @@ func publicize(input string) string {
 
 func privatize(input string) string {
 	return strings.ToLower(input[0:1]) + input[1:]
-}
\ No newline at end of file
+}
+
+// fieldForSignature builds the *ast.Field methodDecls/structFields expect
+// for a method whose signature we only have as a *types.Signature
+// (go/types), not as source AST - either because it was contributed by an
+// embedded interface, or because resolvedInterface is our source of truth
+// for every method once it's available (see methods).
+func (gen *generator) fieldForSignature(name string, sig *types.Signature) *ast.Field {
+	return &ast.Field{
+		Names: []*ast.Ident{ast.NewIdent(name)},
+		Type: &ast.FuncType{
+			Params:  gen.fieldListForTuple(sig.Params(), sig.Variadic()),
+			Results: gen.fieldListForTuple(sig.Results(), false),
+		},
+	}
+}
+
+func (gen *generator) fieldListForTuple(tuple *types.Tuple, variadic bool) *ast.FieldList {
+	if tuple.Len() == 0 {
+		return nil
+	}
+
+	fields := []*ast.Field{}
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+
+		typExpr := gen.typeToExpr(v.Type())
+		if variadic && i == tuple.Len()-1 {
+			if slice, ok := v.Type().(*types.Slice); ok {
+				typExpr = &ast.Ellipsis{Elt: gen.typeToExpr(slice.Elem())}
+			}
+		}
+
+		var names []*ast.Ident
+		if v.Name() != "" {
+			names = []*ast.Ident{ast.NewIdent(v.Name())}
+		}
+
+		fields = append(fields, &ast.Field{Names: names, Type: typExpr})
+	}
+
+	return &ast.FieldList{List: fields}
+}
+
+// typeToExpr converts a go/types.Type into the ast.Expr the generator's
+// printer-based output expects. It covers the type shapes that show up in
+// everyday interfaces (basics, named types, pointers, slices/arrays, maps,
+// channels); anything more exotic falls back to its string form. Named
+// types outside the interface's own package (or inside it, when the fake
+// is generated into a different package - the common foofakes pattern) are
+// qualified with their package name.
+func (gen *generator) typeToExpr(t types.Type) ast.Expr {
+	switch t := t.(type) {
+	case *types.Basic:
+		return ast.NewIdent(t.Name())
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() == nil || (gen.samePackage() && obj.Pkg().Path() == gen.interfacePackagePath) {
+			return ast.NewIdent(obj.Name())
+		}
+		return &ast.SelectorExpr{
+			X:   ast.NewIdent(obj.Pkg().Name()),
+			Sel: ast.NewIdent(obj.Name()),
+		}
+	case *types.Pointer:
+		return &ast.StarExpr{X: gen.typeToExpr(t.Elem())}
+	case *types.Slice:
+		return &ast.ArrayType{Elt: gen.typeToExpr(t.Elem())}
+	case *types.Array:
+		return &ast.ArrayType{
+			Len: &ast.BasicLit{Kind: token.INT, Value: fmt.Sprintf("%d", t.Len())},
+			Elt: gen.typeToExpr(t.Elem()),
+		}
+	case *types.Map:
+		return &ast.MapType{
+			Key:   gen.typeToExpr(t.Key()),
+			Value: gen.typeToExpr(t.Elem()),
+		}
+	case *types.Chan:
+		dir := ast.SEND | ast.RECV
+		switch t.Dir() {
+		case types.SendOnly:
+			dir = ast.SEND
+		case types.RecvOnly:
+			dir = ast.RECV
+		}
+		return &ast.ChanType{Dir: dir, Value: gen.typeToExpr(t.Elem())}
+	case *types.Interface:
+		if t.NumMethods() == 0 {
+			return &ast.InterfaceType{Methods: &ast.FieldList{}}
+		}
+		return ast.NewIdent(t.String())
+	default:
+		return ast.NewIdent(t.String())
+	}
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// parseInterface parses src (a single-file package declaring exactly one
+// interface named name) and returns both its AST and type-checked forms,
+// mirroring what cmd/counterfeiter feeds GenerateFake.
+func parseInterface(t *testing.T, pkgPath, src, name string) (*ast.InterfaceType, *types.Interface) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "iface.go", src, 0)
+	if err != nil {
+		t.Fatalf("parsing fixture: %v", err)
+	}
+
+	conf := types.Config{Importer: nil}
+	pkg, err := conf.Check(pkgPath, fset, []*ast.File{f}, nil)
+	if err != nil {
+		t.Fatalf("type-checking fixture: %v", err)
+	}
+
+	obj := pkg.Scope().Lookup(name)
+	if obj == nil {
+		t.Fatalf("fixture does not declare %s", name)
+	}
+	resolved := obj.Type().(*types.Named).Underlying().(*types.Interface).Complete()
+
+	var node *ast.InterfaceType
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if ok && typeSpec.Name.Name == name {
+				node = typeSpec.Type.(*ast.InterfaceType)
+			}
+		}
+	}
+	if node == nil {
+		t.Fatalf("fixture does not declare %s", name)
+	}
+
+	return node, resolved
+}
+
+// compile writes src as the only file of its own module under t.TempDir()
+// and runs `go build` against it, failing the test if it doesn't compile.
+// This is the round-trip check the generator's output must always pass:
+// a fake isn't useful if the code it generates doesn't build.
+func compile(t *testing.T, src string) {
+	t.Helper()
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, src)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module fake\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "fake.go"), formatted, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated fake does not compile: %v\n%s\n%s", err, out, formatted)
+	}
+}
+
+func TestGenerateFake_Variadic(t *testing.T) {
+	src := `package variadicpkg
+
+type Fooer interface {
+	Foo(format string, args ...interface{}) (int, error)
+}
+`
+	node, resolved := parseInterface(t, "variadicpkg", src, "Fooer")
+
+	for _, tc := range []struct {
+		name     string
+		resolved *types.Interface
+	}{
+		{"ASTOnly", nil},
+		{"Resolved", resolved},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := GenerateFake("FakeFooer", "variadicpkg", "", "", node, tc.resolved, nil)
+			if err != nil {
+				t.Fatalf("GenerateFake: %v", err)
+			}
+			if !strings.Contains(out, "fake.FooStub(format, args...)") {
+				t.Errorf("forwarding call does not spread the variadic slice, got:\n%s", out)
+			}
+			compile(t, out)
+		})
+	}
+}
+
+func TestGenerateFake_CrossPackageAssertion(t *testing.T) {
+	src := `package variadicpkg
+
+type Fooer interface {
+	Foo(s string) (int, error)
+}
+`
+	node, resolved := parseInterface(t, "variadicpkg", src, "Fooer")
+
+	out, err := GenerateFake("FakeFooer", "variadicpkgfakes", "variadicpkg", "Fooer", node, resolved, nil)
+	if err != nil {
+		t.Fatalf("GenerateFake: %v", err)
+	}
+	if !strings.Contains(out, `"variadicpkg"`) {
+		t.Errorf("import block is missing the interface's own package, got:\n%s", out)
+	}
+	if !strings.Contains(out, "var _ variadicpkg.Fooer = new(FakeFooer)") {
+		t.Errorf("assertion is missing or wrongly qualified, got:\n%s", out)
+	}
+	// variadicpkg isn't an importable module from this test's temp dir, so
+	// only the import/assertion text above is checked here; the full
+	// generate-then-compile round trip for the common fakes-package layout
+	// is covered by TestRun_CrossPackageImport in cmd/counterfeiter.
+}